@@ -12,8 +12,10 @@ import (
 
 var (
 	config     Config
+	mu         sync.RWMutex
 	once       sync.Once
 	configFile string = "config.json"
+	secretsDir string
 )
 
 type Config struct {
@@ -28,6 +30,7 @@ type Config struct {
 	UnprotectedMetrics bool               `json:"unprotected_metrics"`
 	Notifications      NotificationConfig `json:"notifications"`
 	S3                 S3Config
+	Storages           map[string]StorageConfig
 	Services           map[string]Service
 	Foreground         bool
 }
@@ -45,10 +48,30 @@ type S3Config struct {
 	Host      string // optional
 	AccessKey string `json:"access_key"` // optional
 	SecretKey string `json:"secret_key"` // optional
+
+	// AgeRecipients, when set, switches the upload pipeline to asymmetric age encryption instead of
+	// EncryptionKey: every recipient (an age public key or ssh-ed25519/ssh-rsa key) can be granted
+	// restore access without sharing a single symmetric secret.
+	AgeRecipients []string `json:"age_recipients,omitempty"`
+	// AgeIdentityFile is the path to an age private key file used to decrypt backups during restore.
+	// Only needed on hosts that actually perform restores - the backup host can hold public keys only.
+	AgeIdentityFile string `json:"age_identity_file,omitempty"`
+
+	// secret_ref variants take precedence over the plain string fields above and over --secrets-dir,
+	// and are re-resolved every time ReloadSecrets runs
+	AccessKeyRef     *SecretRef `json:"access_key_ref,omitempty"`
+	SecretKeyRef     *SecretRef `json:"secret_key_ref,omitempty"`
+	EncryptionKeyRef *SecretRef `json:"encryption_key_ref,omitempty"`
 }
 
 type NotificationConfig struct {
 	Teams TeamsNotificationConfig `json:"teams,omitempty"`
+	// Templates renders notification bodies per event (backup_success, backup_failure,
+	// restore_success, restore_failure) from a Go text/template string.
+	Templates map[string]string `json:"templates,omitempty"`
+	// Transports is a shoutrrr-style list of destination URLs (e.g. slack://, smtp://, discord://,
+	// telegram://, generic+https://) that every rendered notification is sent to.
+	Transports []string `json:"transports,omitempty"`
 }
 
 type TeamsNotificationConfig struct {
@@ -60,6 +83,13 @@ func SetConfigFile(file string) {
 	configFile = file
 }
 
+// SetSecretsDir points backman at a directory of mounted secret files (Kubernetes secret-mount
+// convention), used to auto-populate S3 credentials and service binding credentials. A file found
+// there overrides the value from config.json or env vars; a secret_ref still overrides the file.
+func SetSecretsDir(dir string) {
+	secretsDir = dir
+}
+
 func Init() {
 	Get() // initializes config struct
 }
@@ -68,13 +98,38 @@ func Get() *Config {
 	once.Do(func() {
 		config = *new()
 	})
-	return &config
+
+	mu.RLock()
+	defer mu.RUnlock()
+	c := config
+	// Services and Storages are maps, so the value copy above still aliases the live map
+	// ReloadSecrets mutates under mu.Lock() - copy them too or callers can race a reload.
+	c.Services = copyServices(config.Services)
+	c.Storages = copyStorages(config.Storages)
+	return &c
+}
+
+func copyServices(m map[string]Service) map[string]Service {
+	out := make(map[string]Service, len(m))
+	for name, svc := range m {
+		out[name] = svc
+	}
+	return out
+}
+
+func copyStorages(m map[string]StorageConfig) map[string]StorageConfig {
+	out := make(map[string]StorageConfig, len(m))
+	for name, storageConfig := range m {
+		out[name] = storageConfig
+	}
+	return out
 }
 
 func new() *Config {
 	// initialize
 	config = Config{
 		Services: make(map[string]Service),
+		Storages: make(map[string]StorageConfig),
 	}
 
 	// first, load the config file if it exists
@@ -135,6 +190,12 @@ func new() *Config {
 		if len(envConfig.Notifications.Teams.Events) > 0 {
 			config.Notifications.Teams.Events = envConfig.Notifications.Teams.Events
 		}
+		if len(envConfig.Notifications.Templates) > 0 {
+			config.Notifications.Templates = envConfig.Notifications.Templates
+		}
+		if len(envConfig.Notifications.Transports) > 0 {
+			config.Notifications.Transports = envConfig.Notifications.Transports
+		}
 
 		// s3
 		if envConfig.S3.DisableSSL {
@@ -167,6 +228,17 @@ func new() *Config {
 		if len(envConfig.S3.SecretKey) > 0 {
 			config.S3.SecretKey = envConfig.S3.SecretKey
 		}
+		if len(envConfig.S3.AgeRecipients) > 0 {
+			config.S3.AgeRecipients = envConfig.S3.AgeRecipients
+		}
+		if len(envConfig.S3.AgeIdentityFile) > 0 {
+			config.S3.AgeIdentityFile = envConfig.S3.AgeIdentityFile
+		}
+
+		// storages
+		for storageName, storageConfig := range envConfig.Storages {
+			config.Storages[storageName] = storageConfig
+		}
 
 		// services
 		for serviceName, serviceConfig := range envConfig.Services {
@@ -183,6 +255,15 @@ func new() *Config {
 			if serviceConfig.Retention.Files > 0 {
 				mergedServiceConfig.Retention.Files = serviceConfig.Retention.Files
 			}
+			if serviceConfig.Retention.PruningLeeway.Seconds() > 1 {
+				mergedServiceConfig.Retention.PruningLeeway = serviceConfig.Retention.PruningLeeway
+			}
+			if len(serviceConfig.Retention.PruningPrefix) > 0 {
+				mergedServiceConfig.Retention.PruningPrefix = serviceConfig.Retention.PruningPrefix
+			}
+			if len(serviceConfig.Retention.PruningSchedule) > 0 {
+				mergedServiceConfig.Retention.PruningSchedule = serviceConfig.Retention.PruningSchedule
+			}
 			if serviceConfig.DirectS3 {
 				mergedServiceConfig.DirectS3 = serviceConfig.DirectS3
 			}
@@ -207,6 +288,9 @@ func new() *Config {
 			if len(serviceConfig.RestoreOptions) > 0 {
 				mergedServiceConfig.RestoreOptions = serviceConfig.RestoreOptions
 			}
+			if len(serviceConfig.Destinations) > 0 {
+				mergedServiceConfig.Destinations = serviceConfig.Destinations
+			}
 
 			// bindings
 			if len(serviceConfig.Binding.Type) > 0 {
@@ -277,5 +361,7 @@ func new() *Config {
 		config.Notifications.Teams.Events = events
 	}
 
+	resolveSecrets(&config)
+
 	return &config
 }
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SecretRef points at a secret value that should be loaded from a file or environment variable
+// instead of being embedded directly in config.json, e.g. { "fromFile": "/run/secrets/s3-key" }.
+// This lets operators mount Kubernetes/Docker secrets instead of having to bake them into the config.
+type SecretRef struct {
+	FromFile string `json:"fromFile,omitempty"`
+	FromEnv  string `json:"fromEnv,omitempty"`
+}
+
+// Resolve reads the referenced secret. A zero-value SecretRef resolves to an empty string.
+func (r SecretRef) Resolve() (string, error) {
+	switch {
+	case len(r.FromFile) > 0:
+		data, err := ioutil.ReadFile(r.FromFile)
+		if err != nil {
+			return "", fmt.Errorf("secret_ref: could not read '%s': %w", r.FromFile, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case len(r.FromEnv) > 0:
+		return os.Getenv(r.FromEnv), nil
+	default:
+		return "", nil
+	}
+}
+
+// ReloadSecrets re-resolves every secret_ref and every file found in --secrets-dir, without
+// re-reading config.json, so rotated secrets are picked up without restarting backman.
+func ReloadSecrets() {
+	mu.Lock()
+	defer mu.Unlock()
+	resolveSecrets(&config)
+}
+
+// WatchSecrets calls ReloadSecrets on every tick until stop is closed. Intended to be run in its
+// own goroutine, e.g. `go config.WatchSecrets(5*time.Minute, stopCh)`.
+func WatchSecrets(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ReloadSecrets()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// resolveSecrets applies --secrets-dir files and then secret_ref fields on top of whatever was already
+// loaded from config.json/env, in that order, so secret_ref always wins over a plain file lookup.
+func resolveSecrets(c *Config) {
+	if len(secretsDir) > 0 {
+		if value, ok := readSecretFile(secretsDir, "access-key"); ok {
+			c.S3.AccessKey = value
+		}
+		if value, ok := readSecretFile(secretsDir, "secret-key"); ok {
+			c.S3.SecretKey = value
+		}
+		if value, ok := readSecretFile(secretsDir, "encryption-key"); ok {
+			c.S3.EncryptionKey = value
+		}
+	}
+
+	resolveRef(c.S3.AccessKeyRef, &c.S3.AccessKey, "s3.access_key_ref")
+	resolveRef(c.S3.SecretKeyRef, &c.S3.SecretKey, "s3.secret_key_ref")
+	resolveRef(c.S3.EncryptionKeyRef, &c.S3.EncryptionKey, "s3.encryption_key_ref")
+
+	for name, svc := range c.Services {
+		if len(secretsDir) > 0 {
+			if value, ok := readSecretFile(secretsDir, name+"-password"); ok {
+				svc.Binding.Password = value
+			}
+			if value, ok := readSecretFile(secretsDir, name+"-uri"); ok {
+				svc.Binding.URI = value
+			}
+		}
+
+		resolveRef(svc.Binding.PasswordRef, &svc.Binding.Password, fmt.Sprintf("services.%s.binding.password_ref", name))
+		resolveRef(svc.Binding.URIRef, &svc.Binding.URI, fmt.Sprintf("services.%s.binding.uri_ref", name))
+
+		c.Services[name] = svc
+	}
+}
+
+// resolveRef resolves ref into target if ref is set, logging (but not failing) on error so a single
+// misconfigured or temporarily-unmounted secret doesn't take down the whole config reload.
+func resolveRef(ref *SecretRef, target *string, name string) {
+	if ref == nil {
+		return
+	}
+	value, err := ref.Resolve()
+	if err != nil {
+		log.Printf("could not resolve %s: %v", name, err)
+		return
+	}
+	if len(value) > 0 {
+		*target = value
+	}
+}
+
+// readSecretFile reads <dir>/<name> if it exists, following the Kubernetes secret-mount convention
+// of one file per key. A missing file is not an error - it simply means that secret isn't mounted.
+func readSecretFile(dir, name string) (string, bool) {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Printf("could not read secrets-dir file '%s': %v", path, err)
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
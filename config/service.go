@@ -0,0 +1,56 @@
+package config
+
+import "time"
+
+// Service describes a single bound service instance backman should schedule backups for and
+// expose via the API/UI.
+type Service struct {
+	Name                    string
+	Schedule                string
+	Timeout                 time.Duration
+	Retention               Retention
+	DirectS3                bool     `json:"direct_s3"`
+	DisableColumnStatistics bool     `json:"disable_column_statistics"`
+	LogStdErr               bool     `json:"log_stderr"`
+	ForceImport             bool     `json:"force_import"`
+	LocalBackupPath         string   `json:"local_backup_path"`
+	IgnoreTables            []string `json:"ignore_tables"`
+	BackupOptions           []string `json:"backup_options"`
+	RestoreOptions          []string `json:"restore_options"`
+	// Destinations lists the names of the config.Storages entries a backup of this service should be
+	// fanned out to. Defaults to just the legacy S3 config when left empty.
+	Destinations []string `json:"destinations"`
+	Binding      ServiceBinding
+}
+
+// Retention configures how many backups backman keeps for a service.
+type Retention struct {
+	Days  int
+	Files int
+	// PruningLeeway is a grace period: objects newer than now-Days-PruningLeeway are never pruned,
+	// even if a newer backup already pushed them past the Files count.
+	PruningLeeway time.Duration `json:"pruning_leeway"`
+	// PruningPrefix restricts pruning to objects whose key starts with this prefix, so a bucket
+	// shared with manually uploaded objects doesn't get swept up by accident.
+	PruningPrefix string `json:"pruning_prefix"`
+	// PruningSchedule is an independent cron schedule for the pruning job. Falls back to the
+	// service's regular backup Schedule when left empty.
+	PruningSchedule string `json:"pruning_schedule"`
+}
+
+// ServiceBinding describes how to connect to the bound service instance.
+type ServiceBinding struct {
+	Type     string
+	Provider string
+	Host     string
+	Port     int
+	URI      string `json:"uri"`
+	Username string
+	Password string
+	Database string
+
+	// secret_ref variants take precedence over the plain string fields above and over --secrets-dir,
+	// and are re-resolved every time ReloadSecrets runs
+	PasswordRef *SecretRef `json:"password_ref,omitempty"`
+	URIRef      *SecretRef `json:"uri_ref,omitempty"`
+}
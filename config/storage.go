@@ -0,0 +1,33 @@
+package config
+
+// StorageConfig describes a single named storage destination a backup can be written to or read
+// from. Type selects which of the backend-specific sections below is used.
+type StorageConfig struct {
+	Type  string      `json:"type"` // s3, gcs, azure or local
+	S3    S3Config    `json:"s3,omitempty"`
+	GCS   GCSConfig   `json:"gcs,omitempty"`
+	Azure AzureConfig `json:"azure,omitempty"`
+	Local LocalConfig `json:"local,omitempty"`
+}
+
+// GCSConfig configures a Google Cloud Storage storage destination.
+type GCSConfig struct {
+	BucketName      string `json:"bucket_name"`
+	CredentialsFile string `json:"credentials_file"` // optional, falls back to application default credentials
+	EncryptionKey   string `json:"encryption_key"`
+}
+
+// AzureConfig configures an Azure Blob Storage storage destination.
+type AzureConfig struct {
+	AccountName   string `json:"account_name"`
+	AccountKey    string `json:"account_key"`
+	ContainerName string `json:"container_name"`
+	EncryptionKey string `json:"encryption_key"`
+}
+
+// LocalConfig configures a local filesystem (or mounted NFS) directory as an archive storage
+// destination.
+type LocalConfig struct {
+	Path          string `json:"path"`
+	EncryptionKey string `json:"encryption_key"`
+}
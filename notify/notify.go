@@ -0,0 +1,146 @@
+// Package notify renders backup/restore event notifications from user-supplied Go text/template
+// bodies and dispatches them to a shoutrrr-style list of transport URLs (config.Notifications.Transports),
+// in addition to the legacy Teams webhook (config.Notifications.Teams).
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/containrrr/shoutrrr"
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/log"
+)
+
+// Event names used to look up a template in config.Notifications.Templates.
+const (
+	EventBackupSuccess  = "backup_success"
+	EventBackupFailure  = "backup_failure"
+	EventRestoreSuccess = "restore_success"
+	EventRestoreFailure = "restore_failure"
+)
+
+// StorageStat reports how much data a single destination ended up holding after a backup run.
+type StorageStat struct {
+	Destination string
+	Size        int64
+}
+
+// Stats carries the measurements available once a backup/restore run has finished.
+type Stats struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	BackupSize   int64
+	StorageStats []StorageStat
+}
+
+// Data is the stable data model every notification template is rendered with.
+type Data struct {
+	Service string
+	Backup  string
+	Stats   Stats
+	Error   error
+}
+
+var funcs = template.FuncMap{
+	"formatBytes": formatBytes,
+	"formatTime":  formatTime,
+}
+
+// defaultTemplates are used for an event when config.Notifications.Templates doesn't define one,
+// so operators who already had the legacy Teams webhook configured keep getting a body after
+// upgrading instead of going silent because they never authored a custom template.
+var defaultTemplates = map[string]string{
+	EventBackupSuccess:  "Backup of {{.Service}} completed successfully: {{.Backup}} ({{formatBytes .Stats.BackupSize}}).",
+	EventBackupFailure:  "Backup of {{.Service}} failed: {{.Error}}",
+	EventRestoreSuccess: "Restore of {{.Service}} from {{.Backup}} completed successfully.",
+	EventRestoreFailure: "Restore of {{.Service}} from {{.Backup}} failed: {{.Error}}",
+}
+
+// Send renders the template configured for event (falling back to defaultTemplates when none is
+// configured) and dispatches it to every transport in config.Notifications.Transports, plus the
+// legacy Teams webhook if one is configured for event.
+func Send(event string, data Data) error {
+	cfg := config.Get().Notifications
+
+	tmpl := cfg.Templates[event]
+	if len(tmpl) == 0 {
+		tmpl = defaultTemplates[event]
+	}
+
+	body, err := render(tmpl, data)
+	if err != nil {
+		return fmt.Errorf("notify: could not render template for event [%s]: %w", event, err)
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	var failures int
+	for _, url := range cfg.Transports {
+		if err := shoutrrr.Send(url, body); err != nil {
+			log.Errorf("notify: could not send event [%s]: %v", event, err)
+			failures++
+		}
+	}
+
+	if len(cfg.Teams.Webhook) > 0 && containsEvent(cfg.Teams.Events, event) {
+		if err := sendTeams(cfg.Teams.Webhook, body); err != nil {
+			log.Errorf("notify: could not send event [%s] via teams webhook: %v", event, err)
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("notify: %d transport(s) failed for event [%s]", failures, event)
+	}
+	return nil
+}
+
+func render(tmpl string, data Data) (string, error) {
+	if len(tmpl) == 0 {
+		return "", nil
+	}
+
+	t, err := template.New(data.Service).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func containsEvent(events []string, event string) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBytes renders size as a human-readable binary size, e.g. "42.0 MiB".
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// formatTime renders t in backman's standard log timestamp format.
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
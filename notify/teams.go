@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type teamsMessage struct {
+	Text string `json:"text"`
+}
+
+// sendTeams posts body to a Microsoft Teams incoming webhook.
+func sendTeams(webhook string, body string) error {
+	payload, err := json.Marshal(teamsMessage{Text: body})
+	if err != nil {
+		return fmt.Errorf("teams: could not marshal message: %w", err)
+	}
+
+	resp, err := http.Post(webhook, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("teams: could not post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("teams: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package prune implements retention-based deletion of old backups as its own scheduled job,
+// independent of the ad-hoc pruning that used to run right after each backup completed.
+package prune
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/log"
+	"github.com/swisscom/backman/storage"
+)
+
+// Run works out which objects for svcConfig are beyond its retention policy and, unless dryRun is
+// set, deletes them. It always returns the list of objects that are (or would be) removed.
+func Run(svcConfig config.Service, destination string, dryRun bool) ([]storage.Object, error) {
+	backend, err := storage.Get(destination)
+	if err != nil {
+		return nil, fmt.Errorf("prune: could not resolve destination [%s]: %w", destination, err)
+	}
+
+	objects, err := backend.List(svcConfig.Retention.PruningPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("prune: could not list objects: %w", err)
+	}
+
+	toPrune := expired(objects, svcConfig.Retention)
+	if dryRun {
+		return toPrune, nil
+	}
+
+	for _, object := range toPrune {
+		if err := backend.Delete(object.Key); err != nil {
+			return toPrune, fmt.Errorf("prune: could not delete [%s]: %w", object.Key, err)
+		}
+		log.Infof("pruned backup object [%s]", object.Key)
+	}
+	return toPrune, nil
+}
+
+// expired returns the objects that exceed retention.Days or retention.Files, excluding anything
+// newer than now-Days-PruningLeeway so a grace period always protects recent backups.
+func expired(objects []storage.Object, retention config.Retention) []storage.Object {
+	sorted := make([]storage.Object, len(objects))
+	copy(sorted, objects)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LastModified.After(sorted[j].LastModified)
+	})
+
+	leewayCutoff := time.Now().Add(-time.Duration(retention.Days) * 24 * time.Hour).Add(-retention.PruningLeeway)
+
+	toPrune := make([]storage.Object, 0)
+	for i, object := range sorted {
+		expiredByAge := retention.Days > 0 && object.LastModified.Before(time.Now().Add(-time.Duration(retention.Days)*24*time.Hour))
+		expiredByCount := retention.Files > 0 && i >= retention.Files
+		if !expiredByAge && !expiredByCount {
+			continue
+		}
+		if object.LastModified.After(leewayCutoff) {
+			continue // still within the pruning leeway grace period
+		}
+		toPrune = append(toPrune, object)
+	}
+	return toPrune
+}
+
+// Start builds a cron schedule for every configured service's effective pruning schedule
+// (PruningSchedule, falling back to the service's backup Schedule) and runs Run against each of its
+// destinations in the background until stop is closed. Intended to be run in its own goroutine, e.g.
+// `go prune.Start(stopCh)`, mirroring config.WatchSecrets's goroutine + stop-channel convention.
+func Start(stop <-chan struct{}) {
+	sched := cron.New()
+	for serviceName, svcConfig := range config.Get().Services {
+		schedule := svcConfig.Retention.PruningSchedule
+		if len(schedule) == 0 {
+			schedule = svcConfig.Schedule
+		}
+		if len(schedule) == 0 {
+			continue
+		}
+
+		svcConfig := svcConfig
+		serviceName := serviceName
+		destinations := svcConfig.Destinations
+		if len(destinations) == 0 {
+			destinations = []string{""} // legacy default S3 destination
+		}
+
+		if _, err := sched.AddFunc(schedule, func() {
+			for _, destination := range destinations {
+				if _, err := Run(svcConfig, destination, false); err != nil {
+					log.Errorf("prune: scheduled run for [%s] destination [%s] failed: %v", serviceName, destination, err)
+				}
+			}
+		}); err != nil {
+			log.Errorf("prune: invalid schedule [%s] for service [%s]: %v", schedule, serviceName, err)
+		}
+	}
+
+	sched.Start()
+	<-stop
+	sched.Stop()
+}
+
+// Next returns the next time pruning is scheduled to run for a service. PruningSchedule falls back
+// to the service's regular backup Schedule when left empty, and the zero Time is returned when
+// neither is configured.
+func Next(svcConfig config.Service) time.Time {
+	schedule := svcConfig.Retention.PruningSchedule
+	if len(schedule) == 0 {
+		schedule = svcConfig.Schedule
+	}
+	if len(schedule) == 0 {
+		return time.Time{}
+	}
+
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		log.Errorf("prune: invalid schedule [%s]: %v", schedule, err)
+		return time.Time{}
+	}
+	return sched.Next(time.Now())
+}
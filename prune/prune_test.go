@@ -0,0 +1,58 @@
+package prune
+
+import (
+	"testing"
+	"time"
+
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/storage"
+)
+
+func objectAge(key string, age time.Duration) storage.Object {
+	return storage.Object{Key: key, LastModified: time.Now().Add(-age)}
+}
+
+func TestExpiredByDays(t *testing.T) {
+	objects := []storage.Object{
+		objectAge("recent", time.Hour),
+		objectAge("old", 48*time.Hour),
+	}
+
+	toPrune := expired(objects, config.Retention{Days: 1})
+	if len(toPrune) != 1 || toPrune[0].Key != "old" {
+		t.Fatalf("expired() = %+v, want only [old]", toPrune)
+	}
+}
+
+func TestExpiredByFileCount(t *testing.T) {
+	objects := []storage.Object{
+		objectAge("newest", 1*time.Hour),
+		objectAge("middle", 2*time.Hour),
+		objectAge("oldest", 3*time.Hour),
+	}
+
+	toPrune := expired(objects, config.Retention{Files: 2})
+	if len(toPrune) != 1 || toPrune[0].Key != "oldest" {
+		t.Fatalf("expired() = %+v, want only [oldest] beyond the 2 most recent", toPrune)
+	}
+}
+
+func TestExpiredRespectsPruningLeeway(t *testing.T) {
+	objects := []storage.Object{
+		objectAge("just-over-days-but-within-leeway", 25*time.Hour),
+	}
+
+	toPrune := expired(objects, config.Retention{Days: 1, PruningLeeway: 2 * time.Hour})
+	if len(toPrune) != 0 {
+		t.Fatalf("expired() = %+v, want nothing pruned while still within the leeway grace period", toPrune)
+	}
+}
+
+func TestExpiredNoRetentionConfigured(t *testing.T) {
+	objects := []storage.Object{objectAge("ancient", 365*24*time.Hour)}
+
+	toPrune := expired(objects, config.Retention{})
+	if len(toPrune) != 0 {
+		t.Fatalf("expired() = %+v, want nothing pruned when Days and Files are both unset", toPrune)
+	}
+}
@@ -57,13 +57,14 @@ func getAPIBackup(backup service.Backup) Backup {
 //   200: Backups
 func (h *Handler) ListBackups(c echo.Context) error {
 	serviceType := c.QueryParam("service_type")
+	destination := c.QueryParam("destination")
 	serviceName, err := url.QueryUnescape(c.Param("service_name"))
 	if err != nil {
 		log.Errorf("%v", err)
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid service name: %v", err))
 	}
 
-	b, err := service.GetBackups(serviceType, serviceName)
+	b, err := service.GetBackups(serviceType, serviceName, destination)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, err.Error())
 	}
@@ -87,13 +88,14 @@ func (h *Handler) ListBackups(c echo.Context) error {
 //   200: Backup
 func (h *Handler) GetBackups(c echo.Context) error {
 	serviceType := c.QueryParam("service_type")
+	destination := c.QueryParam("destination")
 	serviceName, err := url.QueryUnescape(c.Param("service_name"))
 	if err != nil {
 		log.Errorf("%v", err)
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid service name: %v", err))
 	}
 
-	backups, err := service.GetBackups(serviceType, serviceName)
+	backups, err := service.GetBackups(serviceType, serviceName, destination)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, err.Error())
 	}
@@ -187,6 +189,7 @@ func (h *Handler) CreateBackup(c echo.Context) error {
 //   200:
 func (h *Handler) DownloadBackup(c echo.Context) error {
 	serviceType := c.Param("service_type")
+	destination := c.QueryParam("destination")
 	serviceName, err := url.QueryUnescape(c.Param("service_name"))
 	if err != nil {
 		log.Errorf("%v", err)
@@ -198,7 +201,7 @@ func (h *Handler) DownloadBackup(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid filename: %v", err))
 	}
 
-	reader, err := service.ReadBackup(serviceType, serviceName, filename)
+	reader, err := service.ReadBackup(serviceType, serviceName, filename, destination)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
@@ -218,6 +221,7 @@ func (h *Handler) DownloadBackup(c echo.Context) error {
 //   204:
 func (h *Handler) DeleteBackup(c echo.Context) error {
 	serviceType := c.Param("service_type")
+	destination := c.QueryParam("destination")
 	serviceName, err := url.QueryUnescape(c.Param("service_name"))
 	if err != nil {
 		log.Errorf("%v", err)
@@ -229,7 +233,7 @@ func (h *Handler) DeleteBackup(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid filename: %v", err))
 	}
 
-	if err := service.DeleteBackup(serviceType, serviceName, filename); err != nil {
+	if err := service.DeleteBackup(serviceType, serviceName, filename, destination); err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
 	return c.NoContent(http.StatusNoContent)
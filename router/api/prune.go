@@ -0,0 +1,66 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/log"
+	"github.com/swisscom/backman/prune"
+	"github.com/swisscom/backman/service"
+)
+
+// swagger:model PruneResult
+type PruneResult struct {
+	DryRun bool   `json:"DryRun"`
+	Files  []File `json:"Files"`
+}
+
+// swagger:route POST /api/v1/prune/{service_type}/{service_name} prune pruneBackups
+// Applies the retention policy for a service, removing backups that are older than their
+// PruningLeeway grace period allows. Pass dry_run=true to only report what would be deleted.
+//
+// produces:
+// - application/json
+//
+// schemes: http, https
+//
+// responses:
+//   200: PruneResult
+func (h *Handler) Prune(c echo.Context) error {
+	serviceType := c.Param("service_type")
+	destination := c.QueryParam("destination")
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+	serviceName, err := url.QueryUnescape(c.Param("service_name"))
+	if err != nil {
+		log.Errorf("%v", err)
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid service name: %v", err))
+	}
+
+	if !config.IsValidServiceType(serviceType) {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("unsupported service type: %s", serviceType))
+	}
+
+	serviceInstance := service.GetService(serviceType, serviceName)
+	if len(serviceInstance.Name) == 0 {
+		err := fmt.Errorf("could not find service [%s] to prune", serviceName)
+		log.Errorf("%v", err)
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
+
+	svcConfig := config.Get().Services[serviceName]
+	objects, err := prune.Run(svcConfig, destination, dryRun)
+	if err != nil {
+		log.Errorf("prune for service [%s] failed: %v", serviceName, err)
+		return c.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	files := make([]File, 0, len(objects))
+	for _, object := range objects {
+		files = append(files, File{Key: object.Key, Size: object.Size, LastModified: object.LastModified})
+	}
+	return c.JSON(http.StatusOK, PruneResult{DryRun: dryRun, Files: files})
+}
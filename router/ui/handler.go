@@ -2,13 +2,16 @@ package ui
 
 import (
 	"crypto/subtle"
+	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/swisscom/backman/config"
 	"github.com/swisscom/backman/log"
+	"github.com/swisscom/backman/prune"
 	"github.com/swisscom/backman/service"
 )
 
@@ -24,6 +27,7 @@ type Page struct {
 	AllServices map[string][]config.Service
 	Backup      service.Backup
 	Backups     []service.Backup
+	NextPrune   time.Time
 	Error       struct {
 		Code    int
 		Message string
@@ -77,6 +81,32 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.HTTPErrorHandler = h.ErrorHandler
 }
 
+// ServiceHandler renders the page for a single service, including its backup files and its next
+// scheduled pruning run.
+func (h *Handler) ServiceHandler(c echo.Context) error {
+	serviceType := c.Param("service_type")
+	serviceName, err := url.QueryUnescape(c.Param("service_name"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid service name: %v", err))
+	}
+
+	serviceInstance := service.GetService(serviceType, serviceName)
+	if len(serviceInstance.Name) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("could not find service [%s]", serviceName))
+	}
+
+	page := h.newServicePage(serviceInstance.Name, config.Get().Services[serviceName])
+
+	backups, err := service.GetBackups(serviceType, serviceName, "")
+	if err != nil {
+		log.Errorf("%v", err)
+	} else if len(backups) > 0 {
+		page.Backup = backups[0]
+	}
+
+	return c.Render(http.StatusOK, "service.html", page)
+}
+
 func (h *Handler) ErrorHandler(err error, c echo.Context) {
 	code := http.StatusInternalServerError
 	message := "Error"
@@ -105,3 +135,12 @@ func (h *Handler) newPage(title string) *Page {
 		AllServices: h.Services,
 	}
 }
+
+// newServicePage builds a Page scoped to a single service, additionally working out when its next
+// scheduled pruning run will happen so templates can surface it next to the backup schedule.
+func (h *Handler) newServicePage(title string, svc config.Service) *Page {
+	page := h.newPage(title)
+	page.Service = svc
+	page.NextPrune = prune.Next(svc)
+	return page
+}
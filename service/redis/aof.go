@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// streamAOF archives the append-only-file data the instance was just told to rewrite via
+// BGREWRITEAOF, reading it directly off the dir the instance reports via CONFIG GET - the same
+// shared-filesystem assumption restoreRDB already makes when writing an RDB dump back. Redis 7+
+// keeps a manifest plus base/incr files under dir/appenddirname; older redis keeps a single file
+// named appendfilename directly under dir. Either way the result is tarred up relative to dir so
+// restoreAOF can extract it straight back into place.
+func streamAOF(ctx context.Context, host string, port int, password string) (*bytes.Buffer, error) {
+	dir, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "dir")
+	if err != nil {
+		return nil, fmt.Errorf("could not determine data dir: %w", err)
+	}
+
+	aofPath := dir
+	if appendDirname, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "appenddirname"); err == nil && len(appendDirname) > 0 {
+		aofPath = filepath.Join(dir, appendDirname)
+	} else {
+		appendFilename, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "appendfilename")
+		if err != nil {
+			return nil, fmt.Errorf("could not determine append filename: %w", err)
+		}
+		aofPath = filepath.Join(dir, appendFilename)
+	}
+
+	var buf bytes.Buffer
+	if err := tarPath(&buf, dir, aofPath); err != nil {
+		return nil, fmt.Errorf("could not archive [%s]: %w", aofPath, err)
+	}
+	return &buf, nil
+}
+
+// restoreAOF extracts a tar archive previously produced by streamAOF back into the instance's
+// configured dir and asks it to reload the append-only file(s) from disk.
+func restoreAOF(ctx context.Context, reader io.ReadCloser, host string, port int, password string) error {
+	dir, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "dir")
+	if err != nil {
+		return fmt.Errorf("could not determine data dir: %w", err)
+	}
+
+	if err := untar(reader, dir); err != nil {
+		return fmt.Errorf("could not extract aof archive into [%s]: %w", dir, err)
+	}
+
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, "DEBUG", "LOADAOF")...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("redis-cli DEBUG LOADAOF: %v: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// tarPath writes path (a file or a directory, walked recursively) into w as a tar archive, with
+// entry names relative to root so the archive can be extracted straight back under root.
+func tarPath(w io.Writer, root string, path string) error {
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	return filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relPath
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// untar extracts a tar archive produced by tarPath into dest, recreating the relative directory
+// structure it was archived with.
+func untar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, header.Name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return err
+			}
+			if err := writeTarFile(tr, target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeTarFile(r io.Reader, target string, mode os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r)
+	return err
+}
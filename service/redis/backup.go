@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry-community/go-cfenv"
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/log"
+	"github.com/swisscom/backman/notify"
+	"github.com/swisscom/backman/service/util"
+	"github.com/swisscom/backman/state"
+	"github.com/swisscom/backman/storage"
+)
+
+const (
+	// BackupOptionAOF selects an append-only-file export instead of the default RDB snapshot.
+	BackupOptionAOF = "aof"
+)
+
+// Backup triggers a BGSAVE against the bound redis instance - or, if BackupOptionAOF is set in
+// config.Service.BackupOptions, a BGREWRITEAOF followed by an archive of the resulting AOF
+// directory - and streams the result off the instance, fanning it out to every destination
+// configured in config.Service.Destinations (or the legacy default S3 destination if none are set).
+func Backup(ctx context.Context, service util.Service, binding *cfenv.Service, svcConfig config.Service) error {
+	state.BackupQueue(service)
+	startTime := time.Now()
+
+	fail := func(err error) error {
+		log.Errorf("redis backup: %v", err)
+		state.BackupFailure(service)
+		notify.Send(notify.EventBackupFailure, notify.Data{Service: service.Name, Error: err})
+		return err
+	}
+
+	host, port, password, err := credentials(binding)
+	if err != nil {
+		return fail(err)
+	}
+
+	useAOF := util.Contains(svcConfig.BackupOptions, BackupOptionAOF)
+
+	var reader *bytes.Buffer
+	ext := "rdb"
+	if useAOF {
+		ext = "aof"
+		if err := bgRewriteAOF(ctx, host, port, password); err != nil {
+			return fail(fmt.Errorf("could not rewrite aof for [%s]: %w", service.Name, err))
+		}
+		reader, err = streamAOF(ctx, host, port, password)
+		if err != nil {
+			return fail(fmt.Errorf("could not archive aof for [%s]: %w", service.Name, err))
+		}
+	} else {
+		if err := bgSave(ctx, host, port, password); err != nil {
+			return fail(fmt.Errorf("could not trigger bgsave for [%s]: %w", service.Name, err))
+		}
+		reader, err = streamDump(ctx, host, port, password)
+		if err != nil {
+			return fail(fmt.Errorf("could not stream dump for [%s]: %w", service.Name, err))
+		}
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", service.Name, time.Now().Format("20060102_150405"), ext)
+
+	destinations := svcConfig.Destinations
+	if len(destinations) == 0 {
+		destinations = []string{""} // legacy default S3 destination
+	}
+
+	storageStats := make([]notify.StorageStat, 0, len(destinations))
+	for _, destination := range destinations {
+		backend, err := storage.Get(destination)
+		if err != nil {
+			return fail(fmt.Errorf("could not resolve destination [%s]: %w", destination, err))
+		}
+		if err := backend.Upload(filename, bytes.NewReader(reader.Bytes())); err != nil {
+			return fail(fmt.Errorf("could not upload [%s] to destination [%s]: %w", filename, destination, err))
+		}
+		storageStats = append(storageStats, notify.StorageStat{Destination: destination, Size: int64(reader.Len())})
+	}
+
+	state.BackupSuccess(service, filename)
+	notify.Send(notify.EventBackupSuccess, notify.Data{
+		Service: service.Name,
+		Backup:  filename,
+		Stats: notify.Stats{
+			StartTime:    startTime,
+			EndTime:      time.Now(),
+			BackupSize:   int64(reader.Len()),
+			StorageStats: storageStats,
+		},
+	})
+	return nil
+}
+
+// bgSave asks the redis instance to fork and persist its dataset to disk as an RDB file.
+func bgSave(ctx context.Context, host string, port int, password string) error {
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, "BGSAVE")...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("redis-cli BGSAVE: %v: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// bgRewriteAOF asks the redis instance to compact its append-only file in the background.
+func bgRewriteAOF(ctx context.Context, host string, port int, password string) error {
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, "BGREWRITEAOF")...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("redis-cli BGREWRITEAOF: %v: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// streamDump opens a replication connection to the instance via `redis-cli --rdb -` and returns the
+// transferred RDB payload, avoiding the need for filesystem access to the redis host.
+func streamDump(ctx context.Context, host string, port int, password string) (*bytes.Buffer, error) {
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, "--rdb", "-")...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("redis-cli --rdb: %v: %s", err, stderr.String())
+	}
+	return &stdout, nil
+}
@@ -1,23 +1,139 @@
 package redis
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/cloudfoundry-community/go-cfenv"
 	"github.com/swisscom/backman/log"
-	"github.com/swisscom/backman/s3"
+	"github.com/swisscom/backman/notify"
 	"github.com/swisscom/backman/service/util"
 	"github.com/swisscom/backman/state"
+	"github.com/swisscom/backman/storage"
 )
 
-func Restore(ctx context.Context, s3 *s3.Client, service util.Service, binding *cfenv.Service, objectPath string) error {
+// Restore downloads a dump previously created by Backup from the given storage destination and
+// replays it against the bound redis instance. RDB dumps are uploaded into the instance's configured
+// dir/dbfilename and reloaded via DEBUG RELOAD; AOF archives (.aof) are extracted back into the
+// instance's dir and reloaded via DEBUG LOADAOF. An empty destination falls back to the legacy
+// default S3 destination.
+func Restore(ctx context.Context, service util.Service, binding *cfenv.Service, objectPath string, destination string) error {
 	state.RestoreQueue(service)
-
-	log.Errorln("restoring redis is not supported, please contact your redis database administrator")
 	filename := filepath.Base(objectPath)
-	state.RestoreFailure(service, filename)
 
-	return fmt.Errorf("redis restore: unsupported")
+	fail := func(err error) error {
+		log.Errorf("redis restore: %v", err)
+		state.RestoreFailure(service, filename)
+		notify.Send(notify.EventRestoreFailure, notify.Data{Service: service.Name, Backup: filename, Error: err})
+		return err
+	}
+
+	backend, err := storage.Get(destination)
+	if err != nil {
+		return fail(fmt.Errorf("could not resolve destination [%s]: %w", destination, err))
+	}
+
+	reader, err := backend.Download(objectPath)
+	if err != nil {
+		return fail(fmt.Errorf("could not download [%s] from destination [%s]: %w", objectPath, destination, err))
+	}
+	defer reader.Close()
+
+	host, port, password, err := credentials(binding)
+	if err != nil {
+		return fail(err)
+	}
+
+	if strings.HasSuffix(filename, ".aof") {
+		if err := restoreAOF(ctx, reader, host, port, password); err != nil {
+			return fail(fmt.Errorf("could not reload aof [%s]: %w", filename, err))
+		}
+	} else if err := restoreRDB(ctx, reader, host, port, password); err != nil {
+		return fail(fmt.Errorf("could not reload rdb [%s]: %w", filename, err))
+	}
+
+	state.RestoreSuccess(service, filename)
+	notify.Send(notify.EventRestoreSuccess, notify.Data{Service: service.Name, Backup: filename})
+	return nil
+}
+
+// restoreRDB uploads the dump into the instance's configured dir/dbfilename and asks it to reload the
+// dataset from disk. This requires the redis instance to permit DEBUG commands.
+func restoreRDB(ctx context.Context, reader io.ReadCloser, host string, port int, password string) error {
+	dir, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "dir")
+	if err != nil {
+		return fmt.Errorf("could not determine data dir: %w", err)
+	}
+	dbfilename, err := redisCliQuery(ctx, host, port, password, "CONFIG", "GET", "dbfilename")
+	if err != nil {
+		return fmt.Errorf("could not determine dbfilename: %w", err)
+	}
+
+	dumpPath := filepath.Join(dir, dbfilename)
+	if err := util.WriteFile(dumpPath, reader); err != nil {
+		return fmt.Errorf("could not write rdb to [%s]: %w", dumpPath, err)
+	}
+
+	// NOSAVE: without it DEBUG RELOAD first saves the in-memory dataset over the dump just written
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, "DEBUG", "RELOAD", "NOSAVE")...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("redis-cli DEBUG RELOAD: %v: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// redisCliQuery runs a redis-cli command that returns a single-line value, e.g. a CONFIG GET reply.
+func redisCliQuery(ctx context.Context, host string, port int, password string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "redis-cli", redisCliArgs(host, port, password, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%v: %s", err, stderr.String())
+	}
+
+	// CONFIG GET replies with the parameter name followed by its value on the next line
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		return "", fmt.Errorf("unexpected redis-cli reply: %q", stdout.String())
+	}
+	return strings.TrimSpace(lines[1]), nil
+}
+
+// credentials extracts the connection details backman needs from the bound redis service.
+func credentials(binding *cfenv.Service) (host string, port int, password string, err error) {
+	host, err = binding.CredentialString("host")
+	if err != nil {
+		return "", 0, "", fmt.Errorf("binding: %w", err)
+	}
+	portString, err := binding.CredentialString("port")
+	if err != nil {
+		return "", 0, "", fmt.Errorf("binding: %w", err)
+	}
+	port, err = strconv.Atoi(portString)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("binding: invalid port %q: %w", portString, err)
+	}
+	password, _ = binding.CredentialString("password") // optional, some instances run unprotected
+	return host, port, password, nil
+}
+
+// redisCliArgs builds the common connection flags for a redis-cli invocation, appending any extra
+// args. Raw mode (the default when stdout isn't a tty) is relied upon by redisCliQuery, which parses
+// a CONFIG GET reply as a bare value on the second line - --no-raw's quoted/numbered reply format
+// would break that parse.
+func redisCliArgs(host string, port int, password string, args ...string) []string {
+	base := []string{"-h", host, "-p", strconv.Itoa(port)}
+	if len(password) > 0 {
+		base = append(base, "-a", password, "--no-auth-warning")
+	}
+	return append(base, args...)
 }
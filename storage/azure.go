@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/swisscom/backman/config"
+)
+
+// AzureBackend stores backups in an Azure Blob Storage container.
+type AzureBackend struct {
+	container     azblob.ContainerURL
+	encryptionKey string
+}
+
+// NewAzureBackend creates a Backend backed by an Azure Blob Storage container. If cfg.EncryptionKey
+// is set, every upload is encrypted at rest with it and transparently decrypted again on download.
+func NewAzureBackend(cfg config.AzureConfig) (*AzureBackend, error) {
+	credential, err := azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("azure: invalid credentials: %w", err)
+	}
+
+	containerURL, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net/%s", cfg.AccountName, cfg.ContainerName))
+	if err != nil {
+		return nil, fmt.Errorf("azure: invalid container url: %w", err)
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	return &AzureBackend{
+		container:     azblob.NewContainerURL(*containerURL, pipeline),
+		encryptionKey: cfg.EncryptionKey,
+	}, nil
+}
+
+func (b *AzureBackend) Upload(objectPath string, reader io.Reader) error {
+	var buf bytes.Buffer
+	if err := copyEncrypted(nopWriteCloser{&buf}, reader, b.encryptionKey); err != nil {
+		return fmt.Errorf("azure: could not read [%s]: %w", objectPath, err)
+	}
+
+	blockBlob := b.container.NewBlockBlobURL(objectPath)
+	if _, err := azblob.UploadBufferToBlockBlob(context.Background(), buf.Bytes(), blockBlob, azblob.UploadToBlockBlobOptions{}); err != nil {
+		return fmt.Errorf("azure: could not upload [%s]: %w", objectPath, err)
+	}
+	return nil
+}
+
+func (b *AzureBackend) Download(objectPath string) (io.ReadCloser, error) {
+	blockBlob := b.container.NewBlockBlobURL(objectPath)
+	response, err := blockBlob.Download(context.Background(), 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("azure: could not download [%s]: %w", objectPath, err)
+	}
+
+	reader, err := decryptReader(response.Body(azblob.RetryReaderOptions{}), b.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("azure: could not decrypt [%s]: %w", objectPath, err)
+	}
+	return reader, nil
+}
+
+func (b *AzureBackend) List(prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		list, err := b.container.ListBlobsFlatSegment(context.Background(), marker, azblob.ListBlobsSegmentOptions{Prefix: prefix})
+		if err != nil {
+			return nil, fmt.Errorf("azure: could not list [%s]: %w", prefix, err)
+		}
+		for _, blob := range list.Segment.BlobItems {
+			objects = append(objects, Object{Key: blob.Name, Size: *blob.Properties.ContentLength, LastModified: blob.Properties.LastModified})
+		}
+		marker = list.NextMarker
+	}
+	return objects, nil
+}
+
+func (b *AzureBackend) Delete(objectPath string) error {
+	blockBlob := b.container.NewBlockBlobURL(objectPath)
+	if _, err := blockBlob.Delete(context.Background(), azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{}); err != nil {
+		return fmt.Errorf("azure: could not delete [%s]: %w", objectPath, err)
+	}
+	return nil
+}
+
+func (b *AzureBackend) Stat(objectPath string) (Object, error) {
+	blockBlob := b.container.NewBlockBlobURL(objectPath)
+	props, err := blockBlob.GetProperties(context.Background(), azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return Object{}, fmt.Errorf("azure: could not stat [%s]: %w", objectPath, err)
+	}
+	return Object{Key: objectPath, Size: props.ContentLength(), LastModified: props.LastModified()}, nil
+}
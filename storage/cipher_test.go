@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCopyEncryptedRoundTrip(t *testing.T) {
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+
+	var encrypted bytes.Buffer
+	if err := copyEncrypted(nopWriteCloser{&encrypted}, bytes.NewReader(plaintext), "correct horse battery staple"); err != nil {
+		t.Fatalf("copyEncrypted: %v", err)
+	}
+
+	if bytes.Equal(encrypted.Bytes(), plaintext) {
+		t.Fatalf("ciphertext matches plaintext, encryption did not run")
+	}
+
+	decrypted, err := decryptReader(nopReadCloser{bytes.NewReader(encrypted.Bytes())}, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("decryptReader: %v", err)
+	}
+	defer decrypted.Close()
+
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(decrypted); err != nil {
+		t.Fatalf("reading decrypted stream: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Fatalf("decrypted payload = %q, want %q", out.Bytes(), plaintext)
+	}
+}
+
+func TestCopyEncryptedWrongPassphrase(t *testing.T) {
+	var encrypted bytes.Buffer
+	if err := copyEncrypted(nopWriteCloser{&encrypted}, bytes.NewReader([]byte("secret data")), "right passphrase"); err != nil {
+		t.Fatalf("copyEncrypted: %v", err)
+	}
+
+	if _, err := decryptReader(nopReadCloser{bytes.NewReader(encrypted.Bytes())}, "wrong passphrase"); err == nil {
+		t.Fatalf("decryptReader succeeded with the wrong passphrase, want an error")
+	}
+}
+
+func TestCopyEncryptedNoPassphrase(t *testing.T) {
+	plaintext := []byte("stored as-is")
+
+	var out bytes.Buffer
+	if err := copyEncrypted(nopWriteCloser{&out}, bytes.NewReader(plaintext), ""); err != nil {
+		t.Fatalf("copyEncrypted: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), plaintext) {
+		t.Fatalf("output = %q, want plaintext %q unchanged when passphrase is empty", out.Bytes(), plaintext)
+	}
+}
+
+type nopReadCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadCloser) Close() error { return nil }
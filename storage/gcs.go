@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"github.com/swisscom/backman/config"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSBackend stores backups in a Google Cloud Storage bucket.
+type GCSBackend struct {
+	client        *storage.Client
+	bucket        string
+	encryptionKey string
+}
+
+// NewGCSBackend creates a Backend backed by a Google Cloud Storage bucket. If cfg.EncryptionKey is
+// set, every upload is encrypted at rest with it and transparently decrypted again on download.
+func NewGCSBackend(cfg config.GCSConfig) (*GCSBackend, error) {
+	var opts []option.ClientOption
+	if len(cfg.CredentialsFile) > 0 {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not create client: %w", err)
+	}
+	return &GCSBackend{client: client, bucket: cfg.BucketName, encryptionKey: cfg.EncryptionKey}, nil
+}
+
+func (b *GCSBackend) Upload(objectPath string, reader io.Reader) error {
+	ctx := context.Background()
+	writer := b.client.Bucket(b.bucket).Object(objectPath).NewWriter(ctx)
+	if err := copyEncrypted(writer, reader, b.encryptionKey); err != nil {
+		return fmt.Errorf("gcs: could not upload [%s]: %w", objectPath, err)
+	}
+	return nil
+}
+
+func (b *GCSBackend) Download(objectPath string) (io.ReadCloser, error) {
+	reader, err := b.client.Bucket(b.bucket).Object(objectPath).NewReader(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not download [%s]: %w", objectPath, err)
+	}
+
+	plaintext, err := decryptReader(reader, b.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not decrypt [%s]: %w", objectPath, err)
+	}
+	return plaintext, nil
+}
+
+func (b *GCSBackend) List(prefix string) ([]Object, error) {
+	ctx := context.Background()
+	it := b.client.Bucket(b.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	objects := make([]Object, 0)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gcs: could not list [%s]: %w", prefix, err)
+		}
+		objects = append(objects, Object{Key: attrs.Name, Size: attrs.Size, LastModified: attrs.Updated})
+	}
+	return objects, nil
+}
+
+func (b *GCSBackend) Delete(objectPath string) error {
+	if err := b.client.Bucket(b.bucket).Object(objectPath).Delete(context.Background()); err != nil {
+		return fmt.Errorf("gcs: could not delete [%s]: %w", objectPath, err)
+	}
+	return nil
+}
+
+func (b *GCSBackend) Stat(objectPath string) (Object, error) {
+	attrs, err := b.client.Bucket(b.bucket).Object(objectPath).Attrs(context.Background())
+	if err != nil {
+		return Object{}, fmt.Errorf("gcs: could not stat [%s]: %w", objectPath, err)
+	}
+	return Object{Key: attrs.Name, Size: attrs.Size, LastModified: attrs.Updated}, nil
+}
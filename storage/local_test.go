@@ -0,0 +1,38 @@
+package storage
+
+import "testing"
+
+func TestLocalBackendResolve(t *testing.T) {
+	b := &LocalBackend{path: "/data/backups"}
+
+	tests := []struct {
+		name       string
+		objectPath string
+		want       string
+		wantErr    bool
+	}{
+		{name: "plain path", objectPath: "mysql/db1/dump.sql.gz", want: "/data/backups/mysql/db1/dump.sql.gz"},
+		{name: "leading slash", objectPath: "/mysql/db1/dump.sql.gz", want: "/data/backups/mysql/db1/dump.sql.gz"},
+		{name: "path traversal", objectPath: "../../etc/passwd", wantErr: true},
+		{name: "path traversal with leading slash", objectPath: "/../../etc/passwd", wantErr: true},
+		{name: "traversal that cancels out stays inside root", objectPath: "mysql/../mysql/db1/dump.sql.gz", want: "/data/backups/mysql/db1/dump.sql.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.resolve(tt.objectPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolve(%q) = %q, want error", tt.objectPath, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve(%q) returned unexpected error: %v", tt.objectPath, err)
+			}
+			if got != tt.want {
+				t.Fatalf("resolve(%q) = %q, want %q", tt.objectPath, got, tt.want)
+			}
+		})
+	}
+}
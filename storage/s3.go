@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/swisscom/backman/config"
+	"github.com/swisscom/backman/s3"
+)
+
+// S3Backend adapts the existing s3.Client to the Backend interface.
+type S3Backend struct {
+	client *s3.Client
+	cfg    config.S3Config
+}
+
+// NewS3Backend creates a Backend backed by an S3-compatible bucket. If cfg.AgeRecipients is set,
+// every upload is additionally wrapped in age encryption so restore access no longer requires
+// sharing the single symmetric cfg.EncryptionKey.
+func NewS3Backend(cfg config.S3Config) (*S3Backend, error) {
+	client, err := s3.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Backend{client: client, cfg: cfg}, nil
+}
+
+// Upload streams reader to objectPath, wrapping it in age encryption first if b.cfg.AgeRecipients
+// is configured.
+func (b *S3Backend) Upload(objectPath string, reader io.Reader) error {
+	if len(b.cfg.AgeRecipients) == 0 {
+		return b.client.Upload(objectPath, reader)
+	}
+
+	pr, pw := io.Pipe()
+	encWriter, err := wrapEncrypt(b.cfg, pw)
+	if err != nil {
+		pw.Close()
+		return err
+	}
+
+	copyErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(encWriter, reader)
+		if closeErr := encWriter.Close(); err == nil {
+			err = closeErr
+		}
+		copyErr <- err
+		_ = pw.CloseWithError(err)
+	}()
+
+	if err := b.client.Upload(objectPath, pr); err != nil {
+		_ = pr.CloseWithError(err) // unblock the copy goroutine so it doesn't leak
+		<-copyErr
+		return fmt.Errorf("s3: could not upload [%s]: %w", objectPath, err)
+	}
+	return <-copyErr
+}
+
+// Download returns a stream for objectPath, auto-detecting and unwrapping an age-encrypted payload.
+func (b *S3Backend) Download(objectPath string) (io.ReadCloser, error) {
+	reader, err := b.client.Download(objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := wrapDecrypt(b.cfg, reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return &decryptingReadCloser{Reader: plaintext, closer: reader}, nil
+}
+
+// decryptingReadCloser pairs a decrypted Reader (age or symmetric AES-256-GCM) with the underlying
+// stream's Close.
+type decryptingReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *decryptingReadCloser) Close() error {
+	return r.closer.Close()
+}
+
+func (b *S3Backend) List(prefix string) ([]Object, error) {
+	files, err := b.client.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]Object, 0, len(files))
+	for _, file := range files {
+		objects = append(objects, Object{Key: file.Key, Size: file.Size, LastModified: file.LastModified})
+	}
+	return objects, nil
+}
+
+func (b *S3Backend) Delete(objectPath string) error {
+	return b.client.Delete(objectPath)
+}
+
+func (b *S3Backend) Stat(objectPath string) (Object, error) {
+	file, err := b.client.Stat(objectPath)
+	if err != nil {
+		return Object{}, err
+	}
+	return Object{Key: file.Key, Size: file.Size, LastModified: file.LastModified}, nil
+}
@@ -0,0 +1,77 @@
+// Package storage abstracts the destinations a backup can be written to or read from. Every entry
+// in config.Config.Storages is resolved to a Backend, so service implementations and the API/UI
+// layers no longer need to know whether they're talking to S3, GCS, Azure Blob or a local archive
+// directory.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/swisscom/backman/config"
+)
+
+// Backend is implemented by every supported storage destination.
+type Backend interface {
+	// Upload streams reader to objectPath.
+	Upload(objectPath string, reader io.Reader) error
+	// Download returns a stream for objectPath. Callers must close the returned reader.
+	Download(objectPath string) (io.ReadCloser, error)
+	// List returns every object stored under prefix.
+	List(prefix string) ([]Object, error)
+	// Delete removes objectPath.
+	Delete(objectPath string) error
+	// Stat returns metadata for a single object.
+	Stat(objectPath string) (Object, error)
+}
+
+// Object describes a single stored backup file, independent of which backend holds it.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+var backends = make(map[string]Backend)
+
+// Init resolves every entry in config.Config.Storages into a Backend and makes it available via Get.
+func Init() error {
+	for name, storageConfig := range config.Get().Storages {
+		backend, err := newBackend(storageConfig)
+		if err != nil {
+			return fmt.Errorf("storage: could not initialize destination [%s]: %w", name, err)
+		}
+		backends[name] = backend
+	}
+	return nil
+}
+
+// Get returns the Backend registered for destination. If destination is empty, the legacy top-level
+// S3 config is used, preserving backwards compatibility for single-destination setups.
+func Get(destination string) (Backend, error) {
+	if len(destination) == 0 {
+		return newBackend(config.StorageConfig{Type: "s3", S3: config.Get().S3})
+	}
+
+	backend, ok := backends[destination]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown destination %q", destination)
+	}
+	return backend, nil
+}
+
+func newBackend(storageConfig config.StorageConfig) (Backend, error) {
+	switch storageConfig.Type {
+	case "", "s3":
+		return NewS3Backend(storageConfig.S3)
+	case "gcs":
+		return NewGCSBackend(storageConfig.GCS)
+	case "azure":
+		return NewAzureBackend(storageConfig.Azure)
+	case "local":
+		return NewLocalBackend(storageConfig.Local)
+	default:
+		return nil, fmt.Errorf("storage: unsupported backend type %q", storageConfig.Type)
+	}
+}